@@ -4,11 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"math/rand"
+	"mime/multipart"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -31,6 +37,309 @@ func WithHttpClient(client *http.Client) ClientOption {
 	}
 }
 
+// WithTransport sets the http.Transport used for outgoing requests,
+// replacing whatever Transport the underlying http.Client currently has.
+func WithTransport(transport *http.Transport) ClientOption {
+	return func(c *Client) {
+		c.httpClient.Transport = transport
+	}
+}
+
+// transport returns the client's *http.Transport, creating one (cloned
+// from http.DefaultTransport) if the client doesn't already have one.
+// This lets the WithMaxConnsPerHost/WithMaxIdleConnsPerHost/WithIdleConnTimeout
+// options work without requiring WithTransport first.
+func (c *Client) transport() *http.Transport {
+	if t, ok := c.httpClient.Transport.(*http.Transport); ok && t != nil {
+		return t
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	c.httpClient.Transport = t
+	return t
+}
+
+// WithMaxConnsPerHost sets the transport's MaxConnsPerHost.
+func WithMaxConnsPerHost(n int) ClientOption {
+	return func(c *Client) {
+		c.transport().MaxConnsPerHost = n
+	}
+}
+
+// WithMaxIdleConnsPerHost sets the transport's MaxIdleConnsPerHost. The
+// default http.Transport caps this at 2, which limits throughput for
+// clients that make many concurrent outbound calls to the same host.
+func WithMaxIdleConnsPerHost(n int) ClientOption {
+	return func(c *Client) {
+		c.transport().MaxIdleConnsPerHost = n
+	}
+}
+
+// WithIdleConnTimeout sets the transport's IdleConnTimeout.
+func WithIdleConnTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.transport().IdleConnTimeout = d
+	}
+}
+
+// defaultRetriableStatusCodes are the status codes retried by default when
+// WithRetry is enabled and no custom WithRetryPolicy has been supplied.
+var defaultRetriableStatusCodes = map[int]bool{
+	http.StatusTooManyRequests:    true,
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+// defaultRetryPolicy retries on network errors and on the default set of
+// retriable status codes (429, 502, 503, 504).
+func defaultRetryPolicy(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp != nil && defaultRetriableStatusCodes[resp.StatusCode]
+}
+
+// WithRetry enables the retry subsystem: doRequest sends a failed request
+// again until maxAttempts attempts (the first included) have been made,
+// sleeping min(maxDelay, baseDelay*2^attempt)
+// with full jitter between attempts (or the duration from a Retry-After
+// header, when present on a 429/503 response).
+func WithRetry(maxAttempts int, baseDelay, maxDelay time.Duration) ClientOption {
+	return func(c *Client) {
+		c.maxRetryAttempts = maxAttempts
+		c.retryBaseDelay = baseDelay
+		c.retryMaxDelay = maxDelay
+		if c.retryPolicy == nil {
+			c.retryPolicy = defaultRetryPolicy
+		}
+	}
+}
+
+// WithRetryPolicy overrides which responses/errors are considered retriable.
+// It must be combined with WithRetry, which controls attempt count and delay.
+func WithRetryPolicy(policy func(*http.Response, error) bool) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// === Circuit Breaker ===
+
+// circuitState is the state of a circuitBreaker.
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// ErrCircuitOpen is returned by doRequest when the circuit breaker is open
+// and the request was rejected without being sent.
+var ErrCircuitOpen = errors.New("httpease: circuit breaker is open")
+
+// CircuitBreakerSettings configures WithCircuitBreaker.
+type CircuitBreakerSettings struct {
+	// FailureThreshold is the number of consecutive failures, while
+	// closed, that trips the breaker open.
+	FailureThreshold int
+	// SuccessThreshold is the number of consecutive successes, while
+	// half-open, required to close the breaker again.
+	SuccessThreshold int
+	// OpenTimeout is how long the breaker stays open before allowing a
+	// single half-open probe request through.
+	OpenTimeout time.Duration
+	// IsFailure classifies a response/error as a breaker failure.
+	// Defaults to defaultRetryPolicy's classification if nil.
+	IsFailure func(*http.Response, error) bool
+}
+
+// circuitBreaker is a mutex-guarded three-state (closed/open/half-open)
+// breaker, keyed per-Client.
+type circuitBreaker struct {
+	settings CircuitBreakerSettings
+
+	mu               sync.Mutex
+	state            circuitState
+	consecutiveFails int
+	consecutiveOKs   int
+	openedAt         time.Time
+	probeInFlight    bool
+}
+
+// allow reports whether a request may proceed, flipping an expired open
+// breaker to half-open as a side effect. Only one caller is let through
+// as the half-open probe at a time; the rest are rejected until
+// recordResult reports the probe's outcome.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if time.Since(b.openedAt) < b.settings.OpenTimeout {
+			return false
+		}
+		b.state = circuitHalfOpen
+		b.consecutiveOKs = 0
+		b.probeInFlight = true
+		return true
+	case circuitHalfOpen:
+		if b.probeInFlight {
+			return false
+		}
+		b.probeInFlight = true
+		return true
+	default:
+		return true
+	}
+}
+
+// isOpen reports whether the breaker is currently tripped open.
+func (b *circuitBreaker) isOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == circuitOpen
+}
+
+// trip flips the breaker to open, starting the OpenTimeout countdown.
+// Callers must hold b.mu.
+func (b *circuitBreaker) trip() {
+	b.state = circuitOpen
+	b.openedAt = time.Now()
+	b.consecutiveFails = 0
+	b.consecutiveOKs = 0
+}
+
+// recordResult updates breaker state based on the outcome of a request
+// that was actually sent, clearing the half-open probe slot (if any) so
+// the next caller can proceed.
+func (b *circuitBreaker) recordResult(resp *http.Response, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasHalfOpen := b.state == circuitHalfOpen
+
+	if b.settings.IsFailure(resp, err) {
+		b.consecutiveOKs = 0
+		switch b.state {
+		case circuitHalfOpen:
+			b.trip()
+		case circuitClosed:
+			b.consecutiveFails++
+			if b.consecutiveFails >= b.settings.FailureThreshold {
+				b.trip()
+			}
+		}
+	} else {
+		b.consecutiveFails = 0
+		if b.state == circuitHalfOpen {
+			b.consecutiveOKs++
+			if b.consecutiveOKs >= b.settings.SuccessThreshold {
+				b.state = circuitClosed
+				b.consecutiveOKs = 0
+			}
+		}
+	}
+
+	if wasHalfOpen {
+		b.probeInFlight = false
+	}
+}
+
+// WithCircuitBreaker fails requests fast once FailureThreshold consecutive
+// failures have been observed, without making the HTTP call, until
+// OpenTimeout elapses. Half-open probes are then let through one at a
+// time: SuccessThreshold consecutive successes close the breaker, and any
+// failure reopens it.
+// Combined with WithRetry, retries are not attempted once the breaker
+// trips open mid-request.
+func WithCircuitBreaker(settings CircuitBreakerSettings) ClientOption {
+	if settings.IsFailure == nil {
+		settings.IsFailure = defaultRetryPolicy
+	}
+	return func(c *Client) {
+		c.breaker = &circuitBreaker{settings: settings}
+	}
+}
+
+// BeforeFunc runs immediately before a request is sent, after all
+// RequestOptions have been applied. It may mutate the request directly
+// (e.g. to attach a refreshed auth token) and returns a context to carry
+// state forward to the After hooks.
+type BeforeFunc func(context.Context, *http.Request) context.Context
+
+// AfterFunc runs after a response has been received, even for non-2xx
+// responses and before the HTTPError branch. It may inspect response
+// headers (e.g. to capture rate-limit information into the context) and
+// returns a context to carry that state forward.
+type AfterFunc func(context.Context, *http.Response) context.Context
+
+// RoundTripperMiddleware wraps an http.RoundTripper, allowing full
+// interception of the request/response cycle (tracing, metrics, etc.).
+type RoundTripperMiddleware func(http.RoundTripper) http.RoundTripper
+
+// WithBefore registers hooks run, in order, just before each request is
+// sent. This composes with the existing RequestOption mechanism rather
+// than replacing it.
+func WithBefore(hooks ...BeforeFunc) ClientOption {
+	return func(c *Client) {
+		c.befores = append(c.befores, hooks...)
+	}
+}
+
+// WithAfter registers hooks run, in order, after each response is
+// received.
+func WithAfter(hooks ...AfterFunc) ClientOption {
+	return func(c *Client) {
+		c.afters = append(c.afters, hooks...)
+	}
+}
+
+// WithRoundTripperMiddleware wraps the client's transport with the given
+// middleware, outermost first, so the first middleware sees the request
+// before the others and the response after them.
+func WithRoundTripperMiddleware(mw ...RoundTripperMiddleware) ClientOption {
+	return func(c *Client) {
+		c.rtMiddlewares = append(c.rtMiddlewares, mw...)
+	}
+}
+
+// === Codec ===
+
+// Codec decouples doRequest from JSON, letting callers plug in XML,
+// protobuf, msgpack, or any other wire format.
+type Codec interface {
+	// Encode marshals v and returns the encoded body along with the
+	// Content-Type header that describes it.
+	Encode(v any) (io.Reader, string, error)
+	// Decode unmarshals the body of r into v.
+	Decode(r io.Reader, v any) error
+}
+
+// JSONCodec is the default Codec, backed by encoding/json.
+type JSONCodec struct{}
+
+func (JSONCodec) Encode(v any) (io.Reader, string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, "", err
+	}
+	return bytes.NewReader(data), "application/json", nil
+}
+
+func (JSONCodec) Decode(r io.Reader, v any) error {
+	return json.NewDecoder(r).Decode(v)
+}
+
+// WithCodec overrides the Codec used to encode request bodies and decode
+// response bodies. Defaults to JSONCodec.
+func WithCodec(codec Codec) ClientOption {
+	return func(c *Client) {
+		c.codec = codec
+	}
+}
+
 // === Request Configuration ===
 
 // RequestOption is a functional option for configuring an http.Request.
@@ -43,11 +352,76 @@ func WithHeader(key, value string) RequestOption {
 	}
 }
 
+// WithQuery adds a query parameter to the request, preserving any
+// existing values for the same key. Use WithQueryValues to set several
+// keys, or repeated keys, at once.
+func WithQuery(key, value string) RequestOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Add(key, value)
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// WithQueryValues merges the given url.Values into the request's query
+// string, preserving any existing values.
+func WithQueryValues(values url.Values) RequestOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		for key, vs := range values {
+			for _, v := range vs {
+				q.Add(key, v)
+			}
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// WithPathParam substitutes "{name}" in the endpoint with value. Matches
+// are replaced in the request's path, so this must reference a
+// placeholder that was present in the endpoint passed to Get/Post/etc.
+// value is escaped so it can only ever fill the placeholder's single path
+// segment, even if it contains "/" or "..".
+func WithPathParam(name, value string) RequestOption {
+	placeholder := "{" + name + "}"
+	return func(req *http.Request) {
+		req.URL.Path = strings.ReplaceAll(req.URL.Path, placeholder, url.PathEscape(value))
+		req.URL.RawPath = ""
+	}
+}
+
+// WithBearerToken sets the Authorization header to "Bearer <token>".
+func WithBearerToken(token string) RequestOption {
+	return func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// WithBasicAuth sets the request's HTTP Basic Authentication credentials.
+func WithBasicAuth(user, pass string) RequestOption {
+	return func(req *http.Request) {
+		req.SetBasicAuth(user, pass)
+	}
+}
+
 // === HTTP Client ===
 
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
+
+	maxRetryAttempts int
+	retryBaseDelay   time.Duration
+	retryMaxDelay    time.Duration
+	retryPolicy      func(*http.Response, error) bool
+
+	befores       []BeforeFunc
+	afters        []AfterFunc
+	rtMiddlewares []RoundTripperMiddleware
+
+	codec Codec
+
+	breaker *circuitBreaker
 }
 
 // HTTPError contains detailed information about a non-200 response.
@@ -61,6 +435,23 @@ func (e *HTTPError) Error() string {
 	return fmt.Sprintf("http error: status code %d, status %s, body: %s", e.StatusCode, e.Status, string(e.Body))
 }
 
+// RetryableError wraps the last error of a request that the retry
+// subsystem sent more than once (whether attempts ran out or the last
+// failure was not retriable), so callers can use errors.As to
+// distinguish "failed after retrying" from a plain first-attempt failure.
+type RetryableError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryableError) Error() string {
+	return fmt.Sprintf("request failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *RetryableError) Unwrap() error {
+	return e.Err
+}
+
 // NewClient creates a new Client with the given base URL and options.
 /* 例子：
 // 1. 定义响应结构体
@@ -102,73 +493,269 @@ func NewClient(baseURL string, opts ...ClientOption) *Client {
 		httpClient: &http.Client{
 			Timeout: 60 * time.Second, // 默认超时60秒
 		},
+		codec: JSONCodec{},
 	}
 
 	for _, opt := range opts {
 		opt(client)
 	}
 
+	if len(client.rtMiddlewares) > 0 {
+		rt := client.httpClient.Transport
+		if rt == nil {
+			rt = http.DefaultTransport
+		}
+		for i := len(client.rtMiddlewares) - 1; i >= 0; i-- {
+			rt = client.rtMiddlewares[i](rt)
+		}
+		client.httpClient.Transport = rt
+	}
+
 	return client
 }
 
-func doRequest[T any](ctx context.Context, c *Client, method, endpoint string, body any, reqOpts ...RequestOption) (*T, error) {
+// CloseIdleConnections closes any idle connections held by the client's
+// underlying transport. Useful when tearing down a client that used
+// WithMaxIdleConnsPerHost/WithIdleConnTimeout to keep a larger pool open.
+func (c *Client) CloseIdleConnections() {
+	c.httpClient.CloseIdleConnections()
+}
+
+// resolveURL resolves endpoint against the client's base URL, shared by
+// doRequest, PostMultipart, and GetStream.
+func (c *Client) resolveURL(endpoint string) (string, error) {
 	base, err := url.Parse(c.baseURL)
 	if err != nil {
-		return nil, fmt.Errorf("invalid base URL: %w", err)
+		return "", fmt.Errorf("invalid base URL: %w", err)
 	}
 
 	ref, err := url.Parse(endpoint)
 	if err != nil {
-		return nil, fmt.Errorf("invalid endpoint URL: %w", err)
+		return "", fmt.Errorf("invalid endpoint URL: %w", err)
+	}
+
+	return base.ResolveReference(ref).String(), nil
+}
+
+// runBefores runs the client's Before hooks against req, in order,
+// returning the (possibly updated) context and the request carrying it.
+func (c *Client) runBefores(ctx context.Context, req *http.Request) (context.Context, *http.Request) {
+	for _, before := range c.befores {
+		ctx = before(ctx, req)
+	}
+	if len(c.befores) > 0 {
+		req = req.WithContext(ctx)
+	}
+	return ctx, req
+}
+
+// runAfters runs the client's After hooks against resp, in order,
+// returning the (possibly updated) context.
+func (c *Client) runAfters(ctx context.Context, resp *http.Response) context.Context {
+	for _, after := range c.afters {
+		ctx = after(ctx, resp)
+	}
+	return ctx
+}
+
+// httpErrorFromResponse reads resp's body and wraps it in an HTTPError if
+// the status is outside the 2xx range. It returns (nil, nil) for 2xx
+// responses, shared by doRequest, PostMultipart, and GetStream.
+func httpErrorFromResponse(resp *http.Response) (*HTTPError, error) {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil, nil
+	}
+
+	respBody, readErr := io.ReadAll(resp.Body)
+	if readErr != nil {
+		return nil, fmt.Errorf("received non-2xx status (%s), but failed to read response body: %w", resp.Status, readErr)
 	}
 
-	finalURL := base.ResolveReference(ref).String()
+	return &HTTPError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       respBody,
+	}, nil
+}
+
+func doRequest[T any](ctx context.Context, c *Client, method, endpoint string, body any, reqOpts ...RequestOption) (*T, error) {
+	finalURL, err := c.resolveURL(endpoint)
+	if err != nil {
+		return nil, err
+	}
 
-	var reqBody io.Reader
+	// The body is encoded once up front and replayed as a fresh
+	// bytes.Reader on every attempt so retries can resend it.
+	var bodyBytes []byte
+	contentType := "application/json"
 	if body != nil {
-		data, err := json.Marshal(body)
+		encoded, ct, err := c.codec.Encode(body)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling JSON: %w", err)
+		}
+		data, err := io.ReadAll(encoded)
 		if err != nil {
 			return nil, fmt.Errorf("error marshaling JSON: %w", err)
 		}
-		reqBody = bytes.NewBuffer(data)
+		bodyBytes = data
+		contentType = ct
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, finalURL, reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
-	}
+	var resp *http.Response
+	var doErr error
+	attempts := 0
 
-	req.Header.Set("Content-Type", "application/json")
-	for _, opt := range reqOpts {
-		opt(req)
+	for {
+		attempts++
+
+		if c.breaker != nil && !c.breaker.allow() {
+			return nil, ErrCircuitOpen
+		}
+
+		var reqBody io.Reader
+		if bodyBytes != nil {
+			reqBody = bytes.NewReader(bodyBytes)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, finalURL, reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("error creating request: %w", err)
+		}
+
+		req.Header.Set("Content-Type", contentType)
+		for _, opt := range reqOpts {
+			opt(req)
+		}
+
+		ctx, req = c.runBefores(ctx, req)
+
+		resp, doErr = c.httpClient.Do(req)
+
+		if c.breaker != nil {
+			c.breaker.recordResult(resp, doErr)
+		}
+
+		retry := c.retryPolicy != nil && attempts < c.maxRetryAttempts && c.retryPolicy(resp, doErr)
+		if c.breaker != nil && c.breaker.isOpen() {
+			retry = false
+		}
+		if !retry {
+			break
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		delay := retryDelay(resp, c.retryBaseDelay, c.retryMaxDelay, attempts-1)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
 	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
+	if doErr != nil {
+		wrapped := fmt.Errorf("error making request: %w", doErr)
+		if attempts > 1 {
+			return nil, &RetryableError{Attempts: attempts, Err: wrapped}
+		}
+		return nil, wrapped
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		respBody, readErr := io.ReadAll(resp.Body)
-		if readErr != nil {
-			return nil, fmt.Errorf("received non-2xx status (%s), but failed to read response body: %w", resp.Status, readErr)
-		}
-		return nil, &HTTPError{
-			StatusCode: resp.StatusCode,
-			Status:     resp.Status,
-			Body:       respBody,
+	ctx = c.runAfters(ctx, resp)
+
+	if httpErr, err := httpErrorFromResponse(resp); err != nil {
+		return nil, err
+	} else if httpErr != nil {
+		if attempts > 1 {
+			return nil, &RetryableError{Attempts: attempts, Err: httpErr}
 		}
+		return nil, httpErr
 	}
 
 	var responseBody T
-	if err := json.NewDecoder(resp.Body).Decode(&responseBody); err != nil {
+	if err := c.codec.Decode(resp.Body, &responseBody); err != nil {
 		return nil, fmt.Errorf("error decoding response JSON: %w", err)
 	}
 
 	return &responseBody, nil
 }
 
+// retryDelay computes how long to wait before the next attempt. A
+// Retry-After header on a 429/503 response takes precedence; otherwise it
+// falls back to min(maxDelay, baseDelay*2^attempt) with full jitter.
+func retryDelay(resp *http.Response, baseDelay, maxDelay time.Duration, attempt int) time.Duration {
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return d
+		}
+	}
+
+	// Double backoff one step at a time, clamping to maxDelay as soon as it's
+	// reached so the multiplication can never overflow int64 (a naive
+	// baseDelay*2^attempt computed up front can wrap negative long before
+	// attempt reaches 62, defeating the cap instead of enforcing it).
+	if baseDelay <= 0 {
+		return 0
+	}
+	backoff := baseDelay
+	for i := 0; i < attempt; i++ {
+		if maxDelay > 0 && backoff >= maxDelay {
+			backoff = maxDelay
+			break
+		}
+		next := backoff * 2
+		if next <= backoff {
+			// Overflowed; saturate at maxDelay, or leave backoff as the
+			// largest representable value if there is no cap.
+			if maxDelay > 0 {
+				backoff = maxDelay
+			} else {
+				backoff = time.Duration(math.MaxInt64)
+			}
+			break
+		}
+		backoff = next
+	}
+	if maxDelay > 0 && backoff > maxDelay {
+		backoff = maxDelay
+	}
+	if backoff <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(backoff)))
+}
+
+// parseRetryAfter parses a Retry-After header value, which is either
+// delta-seconds (e.g. "120") or an HTTP-date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
 func Get[T any](ctx context.Context, c *Client, endpoint string, reqOpts ...RequestOption) (*T, error) {
 	return doRequest[T](ctx, c, "GET", endpoint, nil, reqOpts...)
 }
@@ -184,3 +771,104 @@ func Put[T any](ctx context.Context, c *Client, endpoint string, body any, reqOp
 func Delete[T any](ctx context.Context, c *Client, endpoint string, body any, reqOpts ...RequestOption) (*T, error) {
 	return doRequest[T](ctx, c, "DELETE", endpoint, body, reqOpts...)
 }
+
+// PostMultipart sends a multipart/form-data POST request built from fields
+// and files, decoding the response with the client's Codec. Unlike Post,
+// it makes a single attempt: WithRetry and WithCircuitBreaker do not apply.
+func PostMultipart[T any](ctx context.Context, c *Client, endpoint string, fields map[string]string, files map[string]io.Reader, reqOpts ...RequestOption) (*T, error) {
+	finalURL, err := c.resolveURL(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for name, value := range fields {
+		if err := mw.WriteField(name, value); err != nil {
+			return nil, fmt.Errorf("error writing multipart field %q: %w", name, err)
+		}
+	}
+	for name, file := range files {
+		part, err := mw.CreateFormFile(name, name)
+		if err != nil {
+			return nil, fmt.Errorf("error creating multipart file %q: %w", name, err)
+		}
+		if _, err := io.Copy(part, file); err != nil {
+			return nil, fmt.Errorf("error writing multipart file %q: %w", name, err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("error finalizing multipart body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, finalURL, &buf)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	for _, opt := range reqOpts {
+		opt(req)
+	}
+
+	ctx, req = c.runBefores(ctx, req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	ctx = c.runAfters(ctx, resp)
+
+	if httpErr, err := httpErrorFromResponse(resp); err != nil {
+		return nil, err
+	} else if httpErr != nil {
+		return nil, httpErr
+	}
+
+	var responseBody T
+	if err := c.codec.Decode(resp.Body, &responseBody); err != nil {
+		return nil, fmt.Errorf("error decoding response JSON: %w", err)
+	}
+
+	return &responseBody, nil
+}
+
+// GetStream issues a GET request and returns the raw response body
+// unread, for large downloads or server-sent events where forcing a
+// Codec decode would be wasteful or wrong. The caller must close the
+// returned io.ReadCloser.
+func GetStream(ctx context.Context, c *Client, endpoint string, reqOpts ...RequestOption) (io.ReadCloser, *http.Response, error) {
+	finalURL, err := c.resolveURL(endpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, finalURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating request: %w", err)
+	}
+	for _, opt := range reqOpts {
+		opt(req)
+	}
+
+	ctx, req = c.runBefores(ctx, req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error making request: %w", err)
+	}
+
+	ctx = c.runAfters(ctx, resp)
+
+	if httpErr, err := httpErrorFromResponse(resp); err != nil {
+		defer resp.Body.Close()
+		return nil, resp, err
+	} else if httpErr != nil {
+		defer resp.Body.Close()
+		return nil, resp, httpErr
+	}
+
+	return resp.Body, resp, nil
+}
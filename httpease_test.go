@@ -5,9 +5,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -339,3 +343,723 @@ func TestClient(t *testing.T) {
 		}
 	})
 }
+
+func TestRetry(t *testing.T) {
+	t.Run("retries on 503 then succeeds", func(t *testing.T) {
+		var calls int32
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) <= 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"message": "success", "value": 1}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, WithRetry(5, time.Millisecond, 10*time.Millisecond))
+
+		resp, err := Get[testResponse](context.Background(), client, "/")
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if resp.Message != "success" {
+			t.Errorf("Expected message 'success', but got '%s'", resp.Message)
+		}
+		if got := atomic.LoadInt32(&calls); got != 3 {
+			t.Errorf("Expected 3 calls, but got %d", got)
+		}
+	})
+
+	t.Run("returns RetryableError when attempts are exhausted", func(t *testing.T) {
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, WithRetry(3, time.Millisecond, 10*time.Millisecond))
+
+		_, err := Get[testResponse](context.Background(), client, "/")
+		if err == nil {
+			t.Fatal("Expected an error, but got nil")
+		}
+
+		var retryErr *RetryableError
+		if !errors.As(err, &retryErr) {
+			t.Fatalf("Expected error of type *RetryableError, but got %T", err)
+		}
+		if retryErr.Attempts != 3 {
+			t.Errorf("Expected 3 attempts, but got %d", retryErr.Attempts)
+		}
+
+		var httpErr *HTTPError
+		if !errors.As(err, &httpErr) {
+			t.Fatalf("Expected wrapped *HTTPError, but got %v", err)
+		}
+	})
+
+	t.Run("honors Retry-After delta-seconds header", func(t *testing.T) {
+		var calls int32
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"message": "success", "value": 1}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, WithRetry(3, time.Millisecond, 10*time.Millisecond))
+
+		resp, err := Get[testResponse](context.Background(), client, "/")
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if resp.Message != "success" {
+			t.Errorf("Expected message 'success', but got '%s'", resp.Message)
+		}
+	})
+
+	t.Run("does not retry without WithRetry", func(t *testing.T) {
+		var calls int32
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL)
+		_, err := Get[testResponse](context.Background(), client, "/")
+		if err == nil {
+			t.Fatal("Expected an error, but got nil")
+		}
+		var retryErr *RetryableError
+		if errors.As(err, &retryErr) {
+			t.Fatal("Expected a plain HTTPError, not a RetryableError, when retries are disabled")
+		}
+		if got := atomic.LoadInt32(&calls); got != 1 {
+			t.Errorf("Expected exactly 1 call, but got %d", got)
+		}
+	})
+
+	t.Run("custom retry policy", func(t *testing.T) {
+		var calls int32
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL,
+			WithRetry(2, time.Millisecond, 10*time.Millisecond),
+			WithRetryPolicy(func(resp *http.Response, err error) bool {
+				return resp != nil && resp.StatusCode == http.StatusNotFound
+			}),
+		)
+
+		_, err := Get[testResponse](context.Background(), client, "/")
+		if err == nil {
+			t.Fatal("Expected an error, but got nil")
+		}
+		if got := atomic.LoadInt32(&calls); got != 2 {
+			t.Errorf("Expected 2 calls, but got %d", got)
+		}
+	})
+
+	t.Run("retryDelay clamps to maxDelay instead of overflowing for large attempt counts", func(t *testing.T) {
+		baseDelay := 100 * time.Millisecond
+		maxDelay := 30 * time.Second
+
+		for _, attempt := range []int{40, 50, 62, 100} {
+			delay := retryDelay(nil, baseDelay, maxDelay, attempt)
+			if delay < 0 || delay > maxDelay {
+				t.Errorf("attempt %d: expected delay in [0, %v], but got %v", attempt, maxDelay, delay)
+			}
+		}
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("Before hook attaches auth header", func(t *testing.T) {
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer from-before" {
+				http.Error(w, "missing header", http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"message": "success", "value": 1}`))
+		}))
+		defer server.Close()
+
+		before := func(ctx context.Context, req *http.Request) context.Context {
+			req.Header.Set("Authorization", "Bearer from-before")
+			return ctx
+		}
+
+		client := NewClient(server.URL, WithBefore(before))
+		resp, err := Get[testResponse](context.Background(), client, "/")
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if resp.Message != "success" {
+			t.Errorf("Expected message 'success', but got '%s'", resp.Message)
+		}
+	})
+
+	t.Run("After hook runs even on non-2xx responses", func(t *testing.T) {
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-RateLimit-Remaining", "42")
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		var captured string
+		after := func(ctx context.Context, resp *http.Response) context.Context {
+			captured = resp.Header.Get("X-RateLimit-Remaining")
+			return ctx
+		}
+
+		client := NewClient(server.URL, WithAfter(after))
+		_, err := Get[testResponse](context.Background(), client, "/")
+		if err == nil {
+			t.Fatal("Expected an HTTPError, but got nil")
+		}
+		if captured != "42" {
+			t.Errorf("Expected After hook to capture '42', but got '%s'", captured)
+		}
+	})
+
+	t.Run("RoundTripperMiddleware wraps the transport", func(t *testing.T) {
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"message": "success", "value": 1}`))
+		}))
+		defer server.Close()
+
+		var wrapped bool
+		mw := func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				wrapped = true
+				return next.RoundTrip(req)
+			})
+		}
+
+		client := NewClient(server.URL, WithRoundTripperMiddleware(mw))
+		_, err := Get[testResponse](context.Background(), client, "/")
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if !wrapped {
+			t.Error("Expected the RoundTripperMiddleware to have run")
+		}
+	})
+
+	t.Run("Before hook runs for PostMultipart", func(t *testing.T) {
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer from-before" {
+				http.Error(w, "missing header", http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"message": "success", "value": 1}`))
+		}))
+		defer server.Close()
+
+		before := func(ctx context.Context, req *http.Request) context.Context {
+			req.Header.Set("Authorization", "Bearer from-before")
+			return ctx
+		}
+
+		client := NewClient(server.URL, WithBefore(before))
+		resp, err := PostMultipart[testResponse](context.Background(), client, "/upload", nil, map[string]io.Reader{})
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if resp.Message != "success" {
+			t.Errorf("Expected message 'success', but got '%s'", resp.Message)
+		}
+	})
+
+	t.Run("After hook runs for GetStream", func(t *testing.T) {
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-RateLimit-Remaining", "7")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("streamed-bytes"))
+		}))
+		defer server.Close()
+
+		var captured string
+		after := func(ctx context.Context, resp *http.Response) context.Context {
+			captured = resp.Header.Get("X-RateLimit-Remaining")
+			return ctx
+		}
+
+		client := NewClient(server.URL, WithAfter(after))
+		body, _, err := GetStream(context.Background(), client, "/download")
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		defer body.Close()
+
+		if captured != "7" {
+			t.Errorf("Expected After hook to capture '7', but got '%s'", captured)
+		}
+	})
+}
+
+// roundTripperFunc adapts a function to the http.RoundTripper interface.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// upperCaseCodec is a trivial non-JSON Codec used to test WithCodec: it
+// encodes a string body as upper-cased plain text and decodes by
+// upper-casing the response into a *string.
+type upperCaseCodec struct{}
+
+func (upperCaseCodec) Encode(v any) (io.Reader, string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, "", fmt.Errorf("upperCaseCodec only supports string bodies, got %T", v)
+	}
+	return strings.NewReader(strings.ToUpper(s)), "text/plain", nil
+}
+
+func (upperCaseCodec) Decode(r io.Reader, v any) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	out, ok := v.(*string)
+	if !ok {
+		return fmt.Errorf("upperCaseCodec only supports decoding into *string, got %T", v)
+	}
+	*out = strings.ToUpper(string(data))
+	return nil
+}
+
+func TestCodec(t *testing.T) {
+	t.Run("WithCodec overrides request and response encoding", func(t *testing.T) {
+		var gotContentType, gotBody string
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotContentType = r.Header.Get("Content-Type")
+			data, _ := io.ReadAll(r.Body)
+			gotBody = string(data)
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("response"))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, WithCodec(upperCaseCodec{}))
+		resp, err := Post[string](context.Background(), client, "/", "hello")
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if gotContentType != "text/plain" {
+			t.Errorf("Expected Content-Type 'text/plain', but got '%s'", gotContentType)
+		}
+		if gotBody != "HELLO" {
+			t.Errorf("Expected request body 'HELLO', but got '%s'", gotBody)
+		}
+		if *resp != "RESPONSE" {
+			t.Errorf("Expected decoded response 'RESPONSE', but got '%s'", *resp)
+		}
+	})
+}
+
+func TestPostMultipart(t *testing.T) {
+	t.Run("sends fields and files", func(t *testing.T) {
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if r.FormValue("name") != "gopher" {
+				http.Error(w, "missing field", http.StatusBadRequest)
+				return
+			}
+			file, _, err := r.FormFile("avatar")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			defer file.Close()
+			data, _ := io.ReadAll(file)
+			if string(data) != "fake-image-bytes" {
+				http.Error(w, "unexpected file contents", http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"message": "success", "value": 1}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL)
+		resp, err := PostMultipart[testResponse](context.Background(), client, "/upload",
+			map[string]string{"name": "gopher"},
+			map[string]io.Reader{"avatar": strings.NewReader("fake-image-bytes")},
+		)
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if resp.Message != "success" {
+			t.Errorf("Expected message 'success', but got '%s'", resp.Message)
+		}
+	})
+}
+
+func TestGetStream(t *testing.T) {
+	t.Run("returns the raw response body", func(t *testing.T) {
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("streamed-bytes"))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL)
+		body, resp, err := GetStream(context.Background(), client, "/download")
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		defer body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected status 200, but got %d", resp.StatusCode)
+		}
+		data, err := io.ReadAll(body)
+		if err != nil {
+			t.Fatalf("Expected no error reading stream, but got: %v", err)
+		}
+		if string(data) != "streamed-bytes" {
+			t.Errorf("Expected 'streamed-bytes', but got '%s'", string(data))
+		}
+	})
+
+	t.Run("returns HTTPError on non-2xx", func(t *testing.T) {
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("not found"))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL)
+		_, _, err := GetStream(context.Background(), client, "/missing")
+		if err == nil {
+			t.Fatal("Expected an error, but got nil")
+		}
+		var httpErr *HTTPError
+		if !errors.As(err, &httpErr) {
+			t.Fatalf("Expected error of type *HTTPError, but got %T", err)
+		}
+	})
+}
+
+func TestRequestBuilders(t *testing.T) {
+	t.Run("WithQuery and WithQueryValues merge into the query string", func(t *testing.T) {
+		var gotQuery url.Values
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotQuery = r.URL.Query()
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"message": "success", "value": 1}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL)
+		_, err := Get[testResponse](context.Background(), client, "/search",
+			WithQuery("tag", "go"),
+			WithQuery("tag", "http"),
+			WithQueryValues(url.Values{"page": {"2"}}),
+		)
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if got := gotQuery["tag"]; len(got) != 2 || got[0] != "go" || got[1] != "http" {
+			t.Errorf("Expected tag=[go http], but got %v", got)
+		}
+		if gotQuery.Get("page") != "2" {
+			t.Errorf("Expected page=2, but got '%s'", gotQuery.Get("page"))
+		}
+	})
+
+	t.Run("WithPathParam substitutes the endpoint placeholder", func(t *testing.T) {
+		var gotPath string
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotPath = r.URL.Path
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"message": "success", "value": 1}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL)
+		_, err := Get[testResponse](context.Background(), client, "/users/{id}/posts", WithPathParam("id", "42"))
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if gotPath != "/users/42/posts" {
+			t.Errorf("Expected path '/users/42/posts', but got '%s'", gotPath)
+		}
+	})
+
+	t.Run("WithPathParam escapes slashes so the value can't alter the path structure", func(t *testing.T) {
+		var gotPath string
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotPath = r.URL.Path
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"message": "success", "value": 1}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL)
+		_, err := Get[testResponse](context.Background(), client, "/users/{id}/posts", WithPathParam("id", "../../admin"))
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+		if gotPath != "/users/..%2F..%2Fadmin/posts" {
+			t.Errorf("Expected the path param to stay confined to its segment, but got '%s'", gotPath)
+		}
+	})
+
+	t.Run("WithBearerToken sets the Authorization header", func(t *testing.T) {
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer my-token" {
+				http.Error(w, "missing bearer token", http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"message": "success", "value": 1}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL)
+		_, err := Get[testResponse](context.Background(), client, "/", WithBearerToken("my-token"))
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+	})
+
+	t.Run("WithBasicAuth sets the Authorization header", func(t *testing.T) {
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, pass, ok := r.BasicAuth()
+			if !ok || user != "alice" || pass != "secret" {
+				http.Error(w, "missing basic auth", http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"message": "success", "value": 1}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL)
+		_, err := Get[testResponse](context.Background(), client, "/", WithBasicAuth("alice", "secret"))
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+	})
+}
+
+func TestTransportTuning(t *testing.T) {
+	t.Run("WithMaxIdleConnsPerHost creates a transport on demand", func(t *testing.T) {
+		client := NewClient("http://localhost", WithMaxIdleConnsPerHost(50), WithIdleConnTimeout(5*time.Second))
+
+		transport, ok := client.httpClient.Transport.(*http.Transport)
+		if !ok {
+			t.Fatalf("Expected *http.Transport, but got %T", client.httpClient.Transport)
+		}
+		if transport.MaxIdleConnsPerHost != 50 {
+			t.Errorf("Expected MaxIdleConnsPerHost 50, but got %d", transport.MaxIdleConnsPerHost)
+		}
+		if transport.IdleConnTimeout != 5*time.Second {
+			t.Errorf("Expected IdleConnTimeout 5s, but got %v", transport.IdleConnTimeout)
+		}
+	})
+
+	t.Run("tuning options reuse a transport supplied via WithTransport", func(t *testing.T) {
+		transport := &http.Transport{}
+		client := NewClient("http://localhost", WithTransport(transport), WithMaxConnsPerHost(10))
+
+		if client.httpClient.Transport != transport {
+			t.Error("Expected the supplied transport to be reused")
+		}
+		if transport.MaxConnsPerHost != 10 {
+			t.Errorf("Expected MaxConnsPerHost 10, but got %d", transport.MaxConnsPerHost)
+		}
+	})
+
+	t.Run("CloseIdleConnections delegates to the transport", func(t *testing.T) {
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"message": "success", "value": 1}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, WithMaxIdleConnsPerHost(10))
+		_, err := Get[testResponse](context.Background(), client, "/")
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %v", err)
+		}
+
+		// Just verifying this doesn't panic and closes without error.
+		client.CloseIdleConnections()
+	})
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Run("trips open after FailureThreshold failures and rejects without calling", func(t *testing.T) {
+		var calls int32
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, WithCircuitBreaker(CircuitBreakerSettings{
+			FailureThreshold: 2,
+			SuccessThreshold: 1,
+			OpenTimeout:      time.Hour,
+		}))
+
+		for i := 0; i < 2; i++ {
+			_, err := Get[testResponse](context.Background(), client, "/")
+			if err == nil {
+				t.Fatal("Expected an error, but got nil")
+			}
+		}
+
+		_, err := Get[testResponse](context.Background(), client, "/")
+		if !errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("Expected ErrCircuitOpen, but got: %v", err)
+		}
+		if got := atomic.LoadInt32(&calls); got != 2 {
+			t.Errorf("Expected exactly 2 calls to have reached the server, but got %d", got)
+		}
+	})
+
+	t.Run("half-open probe closes the breaker on success", func(t *testing.T) {
+		var failing int32 = 1
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.LoadInt32(&failing) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"message": "success", "value": 1}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, WithCircuitBreaker(CircuitBreakerSettings{
+			FailureThreshold: 1,
+			SuccessThreshold: 1,
+			OpenTimeout:      10 * time.Millisecond,
+		}))
+
+		if _, err := Get[testResponse](context.Background(), client, "/"); err == nil {
+			t.Fatal("Expected the first call to fail")
+		}
+
+		if _, err := Get[testResponse](context.Background(), client, "/"); !errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("Expected ErrCircuitOpen while open, but got: %v", err)
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		atomic.StoreInt32(&failing, 0)
+
+		resp, err := Get[testResponse](context.Background(), client, "/")
+		if err != nil {
+			t.Fatalf("Expected the half-open probe to succeed, but got: %v", err)
+		}
+		if resp.Message != "success" {
+			t.Errorf("Expected message 'success', but got '%s'", resp.Message)
+		}
+
+		if _, err := Get[testResponse](context.Background(), client, "/"); err != nil {
+			t.Fatalf("Expected the breaker to be closed again, but got: %v", err)
+		}
+	})
+
+	t.Run("does not retry once the breaker trips open mid-request", func(t *testing.T) {
+		var calls int32
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL,
+			WithRetry(5, time.Millisecond, 10*time.Millisecond),
+			WithCircuitBreaker(CircuitBreakerSettings{
+				FailureThreshold: 1,
+				SuccessThreshold: 1,
+				OpenTimeout:      time.Hour,
+			}),
+		)
+
+		_, err := Get[testResponse](context.Background(), client, "/")
+		if err == nil {
+			t.Fatal("Expected an error, but got nil")
+		}
+		if got := atomic.LoadInt32(&calls); got != 1 {
+			t.Errorf("Expected the breaker to stop retries after 1 call, but got %d calls", got)
+		}
+	})
+
+	t.Run("only a single half-open probe reaches the server concurrently", func(t *testing.T) {
+		var shouldFail int32 = 1
+		var probeCalls int32
+		server := setupTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.LoadInt32(&shouldFail) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			atomic.AddInt32(&probeCalls, 1)
+			time.Sleep(20 * time.Millisecond) // hold the probe open so racing callers overlap it
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"message": "success", "value": 1}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, WithCircuitBreaker(CircuitBreakerSettings{
+			FailureThreshold: 1,
+			SuccessThreshold: 1,
+			OpenTimeout:      10 * time.Millisecond,
+		}))
+
+		if _, err := Get[testResponse](context.Background(), client, "/"); err == nil {
+			t.Fatal("Expected the priming request to fail and trip the breaker")
+		}
+
+		time.Sleep(20 * time.Millisecond) // let OpenTimeout elapse
+		atomic.StoreInt32(&shouldFail, 0)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 6; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = Get[testResponse](context.Background(), client, "/")
+			}()
+		}
+		wg.Wait()
+
+		if got := atomic.LoadInt32(&probeCalls); got != 1 {
+			t.Errorf("Expected exactly 1 half-open probe to reach the server, but got %d", got)
+		}
+	})
+}